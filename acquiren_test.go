@@ -0,0 +1,210 @@
+package semaphore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSemaphore_AcquireN_Release(t *testing.T) {
+	sem := New(10)
+	ctx := context.Background()
+
+	release, err := sem.AcquireN(ctx, 4)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if sem.Occupied() != 4 {
+		t.Errorf("4 occupied places are expected, %d were obtained", sem.Occupied())
+	}
+
+	release()
+	if sem.Occupied() != 0 {
+		t.Errorf("0 occupied places are expected, %d were obtained", sem.Occupied())
+	}
+}
+
+func TestSemaphore_AcquireN_Timeout(t *testing.T) {
+	sem := New(1)
+	ctx := context.Background()
+
+	if _, err := sem.AcquireN(ctx, 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := sem.AcquireN(timeout, 1); err != errTimeout {
+		t.Errorf("%q error is expected, %q was obtained", errTimeout, err)
+	}
+}
+
+func TestSemaphore_AcquireN_NoStarvation(t *testing.T) {
+	sem := New(2)
+	ctx := context.Background()
+
+	release, err := sem.AcquireN(ctx, 2)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	order := make(chan int, 2)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		release, err := sem.AcquireN(ctx, 2)
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+			return
+		}
+		order <- 2
+		release()
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		release, err := sem.AcquireN(ctx, 1)
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+			return
+		}
+		order <- 1
+		release()
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	release()
+	wg.Wait()
+	close(order)
+
+	if first := <-order; first != 2 {
+		t.Errorf("the head-of-line waiter requesting 2 slots is expected to run first, %d ran first", first)
+	}
+}
+
+func TestSemaphore_AcquireN_StrictFIFO_NoSkipAhead(t *testing.T) {
+	sem := New(2)
+	ctx := context.Background()
+
+	releaseA, err := sem.AcquireN(ctx, 1)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	releaseB, err := sem.AcquireN(ctx, 1)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	bigDone := make(chan error, 1)
+	go func() {
+		release, err := sem.AcquireN(ctx, 2)
+		if err == nil {
+			release()
+		}
+		bigDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	smallDone := make(chan error, 1)
+	go func() {
+		release, err := sem.AcquireN(ctx, 1)
+		if err == nil {
+			release()
+		}
+		smallDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	releaseA()
+
+	select {
+	case <-bigDone:
+		t.Fatal("the big waiter must still be blocked, only 1 of the 2 needed slots was freed")
+	case <-smallDone:
+		t.Fatal("the small waiter must not skip ahead of the earlier, still-unsatisfied big waiter")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releaseB()
+
+	select {
+	case err := <-bigDone:
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the big waiter to be granted once both slots were free")
+	}
+	select {
+	case err := <-smallDone:
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the small waiter to be granted after the big waiter released")
+	}
+}
+
+func TestSemaphore_Release_ConcurrentDoesNotOversubscribe(t *testing.T) {
+	sem := New(1)
+	if _, err := sem.AcquireN(context.Background(), 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = sem.Release()
+		}(i)
+	}
+	wg.Wait()
+
+	successes := 0
+	for _, err := range errs {
+		if err == nil {
+			successes++
+		}
+	}
+	if successes != 1 {
+		t.Errorf("exactly 1 Release call is expected to succeed, %d did", successes)
+	}
+	if sem.Occupied() != 0 {
+		t.Errorf("0 occupied places are expected, %d were obtained", sem.Occupied())
+	}
+}
+
+func TestSemaphore_AcquireN_CanceledWaiterIsDequeued(t *testing.T) {
+	sem := New(1)
+	ctx := context.Background()
+
+	if _, err := sem.AcquireN(ctx, 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		_, err := sem.AcquireN(canceled, 1)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err == nil {
+		t.Error("an error is expected for the canceled waiter")
+	}
+
+	_ = sem.Release()
+	if sem.Occupied() != 0 {
+		t.Errorf("0 occupied places are expected, %d were obtained", sem.Occupied())
+	}
+}
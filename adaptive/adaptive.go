@@ -0,0 +1,168 @@
+// Package adaptive provides a self-tuning concurrency limiter built on top
+// of a semaphore.Resizable. Its capacity grows or shrinks over
+// time based on feedback from pluggable Watchers, e.g. CPU load or memory
+// pressure, making it suitable as a building block for adaptive worker pools.
+package adaptive
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kamilsk/semaphore"
+)
+
+// Watcher reports whether the caller should back off, e.g. because of
+// elevated latency, a rising error rate, or resource pressure.
+type Watcher interface {
+	// Poll checks the current signal and reports whether capacity should shrink.
+	Poll(ctx context.Context) (backoff bool, err error)
+}
+
+// Calculator computes the next capacity of an AdaptiveLimiter from watcher
+// feedback: a multiplicative decrease on backoff, an additive increase
+// otherwise, bounded to [Min, Max].
+type Calculator struct {
+	Min, Max int
+	// Factor is the multiplicative decrease factor applied on backoff,
+	// e.g. 0.75. It must be in (0, 1); values outside that range are
+	// treated as the default of 0.75.
+	Factor float64
+}
+
+// Next returns the capacity that should follow current given whether any
+// watcher reported backoff.
+func (c Calculator) Next(current int, backoff bool) int {
+	factor := c.Factor
+	if factor <= 0 || factor >= 1 {
+		factor = 0.75
+	}
+
+	next := current + 1
+	if backoff {
+		next = int(float64(current) * factor)
+	}
+	if next < c.Min {
+		next = c.Min
+	}
+	if next > c.Max {
+		next = c.Max
+	}
+	return next
+}
+
+// defaultInterval is used in place of a non-positive Config.Interval.
+const defaultInterval = 5 * time.Second
+
+// Config configures an AdaptiveLimiter.
+type Config struct {
+	Calculator
+	// Interval is the period on which watchers are polled and the
+	// capacity is recomputed. Non-positive values default to
+	// defaultInterval.
+	Interval time.Duration
+	// OnResize, if set, is called after every capacity change with the
+	// old and the new capacity. It is meant for exposing Prometheus-style
+	// metrics and must return quickly.
+	OnResize func(old, new int)
+}
+
+// AdaptiveLimiter wraps a semaphore.Resizable and periodically recomputes
+// its capacity from the signals reported by a set of Watchers.
+type AdaptiveLimiter struct {
+	sem      semaphore.Resizable
+	watchers []Watcher
+	cfg      Config
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewAdaptiveLimiter constructs an AdaptiveLimiter that starts at cfg.Max
+// capacity and adjusts it on cfg.Interval using the given watchers.
+func NewAdaptiveLimiter(cfg Config, watchers ...Watcher) *AdaptiveLimiter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	l := &AdaptiveLimiter{
+		sem:      semaphore.NewResizable(cfg.Max),
+		watchers: watchers,
+		cfg:      cfg,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Acquire tries to reduce the number of available slots for 1.
+// The operation can be canceled using context. In this case
+// an appropriate error will be returned.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) (semaphore.ReleaseFunc, error) {
+	return l.sem.Acquire(ctx)
+}
+
+// Capacity returns the current capacity of the limiter.
+func (l *AdaptiveLimiter) Capacity() int {
+	return l.sem.Capacity()
+}
+
+// Occupied returns the current number of occupied slots.
+func (l *AdaptiveLimiter) Occupied() int {
+	return l.sem.Occupied()
+}
+
+// Close stops the background capacity recalculation. It does not release
+// any held slots. It is safe to call Close more than once.
+func (l *AdaptiveLimiter) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+	})
+	return nil
+}
+
+func (l *AdaptiveLimiter) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.recompute()
+		}
+	}
+}
+
+func (l *AdaptiveLimiter) recompute() {
+	ctx := context.Background()
+	backoff := false
+	for _, watcher := range l.watchers {
+		report, err := watcher.Poll(ctx)
+		if err != nil {
+			continue
+		}
+		if report {
+			backoff = true
+			break
+		}
+	}
+
+	old := l.sem.Capacity()
+	next := l.cfg.Calculator.Next(old, backoff)
+	if next == old {
+		return
+	}
+	if err := l.sem.Resize(next); err != nil {
+		return
+	}
+	if l.cfg.OnResize != nil {
+		l.cfg.OnResize(old, next)
+	}
+}
@@ -0,0 +1,166 @@
+package adaptive
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type staticWatcher struct {
+	backoff bool
+	err     error
+}
+
+func (w staticWatcher) Poll(ctx context.Context) (bool, error) { return w.backoff, w.err }
+
+func TestCalculator_Next(t *testing.T) {
+	calc := Calculator{Min: 2, Max: 10, Factor: 0.5}
+
+	if next := calc.Next(4, true); next != 2 {
+		t.Errorf("4 halved is expected to be 2, %d was obtained", next)
+	}
+	if next := calc.Next(4, false); next != 5 {
+		t.Errorf("4 incremented is expected to be 5, %d was obtained", next)
+	}
+	if next := calc.Next(2, true); next != 2 {
+		t.Errorf("capacity must not drop below Min, %d was obtained", next)
+	}
+	if next := calc.Next(10, false); next != 10 {
+		t.Errorf("capacity must not grow above Max, %d was obtained", next)
+	}
+}
+
+func TestCalculator_Next_DefaultFactor(t *testing.T) {
+	calc := Calculator{Min: 1, Max: 100}
+
+	if next := calc.Next(8, true); next != 6 {
+		t.Errorf("8 decreased by the default factor is expected to be 6, %d was obtained", next)
+	}
+}
+
+func TestAdaptiveLimiter_ShrinksOnBackoff(t *testing.T) {
+	watcher := staticWatcher{backoff: true}
+
+	var mu sync.Mutex
+	var resized []int
+	limiter := NewAdaptiveLimiter(Config{
+		Calculator: Calculator{Min: 1, Max: 4, Factor: 0.5},
+		Interval:   10 * time.Millisecond,
+		OnResize: func(old, new int) {
+			mu.Lock()
+			defer mu.Unlock()
+			resized = append(resized, new)
+		},
+	}, watcher)
+	defer limiter.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for limiter.Capacity() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if limiter.Capacity() != 1 {
+		t.Fatalf("capacity equals to 1 is expected, %d was obtained", limiter.Capacity())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(resized) == 0 {
+		t.Error("expected OnResize to be called at least once")
+	}
+}
+
+func TestAdaptiveLimiter_GrowsWithoutBackoff(t *testing.T) {
+	limiter := NewAdaptiveLimiter(Config{
+		Calculator: Calculator{Min: 1, Max: 3, Factor: 0.5},
+		Interval:   10 * time.Millisecond,
+	})
+	defer limiter.Close()
+
+	// the limiter starts at Max, so shrink it first to observe growth
+	if err := limiter.sem.Resize(1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for limiter.Capacity() != 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if limiter.Capacity() != 3 {
+		t.Fatalf("capacity equals to 3 is expected, %d was obtained", limiter.Capacity())
+	}
+}
+
+func TestAdaptiveLimiter_ZeroIntervalDefaults(t *testing.T) {
+	limiter := NewAdaptiveLimiter(Config{
+		Calculator: Calculator{Min: 1, Max: 1},
+	})
+	defer limiter.Close()
+
+	if limiter.cfg.Interval != defaultInterval {
+		t.Errorf("interval equals to %v is expected, %v was obtained", defaultInterval, limiter.cfg.Interval)
+	}
+	if limiter.Capacity() != 1 {
+		t.Errorf("capacity equals to 1 is expected, %d was obtained", limiter.Capacity())
+	}
+}
+
+func TestAdaptiveLimiter_Close_Idempotent(t *testing.T) {
+	limiter := NewAdaptiveLimiter(Config{
+		Calculator: Calculator{Min: 1, Max: 1},
+		Interval:   time.Hour,
+	})
+
+	if err := limiter.Close(); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if err := limiter.Close(); err != nil {
+		t.Fatalf("a second Close call must not error, %q was obtained", err)
+	}
+}
+
+func TestAdaptiveLimiter_Acquire(t *testing.T) {
+	limiter := NewAdaptiveLimiter(Config{
+		Calculator: Calculator{Min: 1, Max: 1},
+		Interval:   time.Hour,
+	})
+	defer limiter.Close()
+
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if limiter.Occupied() != 1 {
+		t.Errorf("1 occupied place is expected, %d was obtained", limiter.Occupied())
+	}
+	release()
+}
+
+func TestMemoryWatcher_Poll(t *testing.T) {
+	w := MemoryWatcher{Threshold: 0}
+	backoff, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if !backoff {
+		t.Error("expected backoff with a zero threshold")
+	}
+}
+
+func TestAdaptiveLimiter_WatcherErrorIsIgnored(t *testing.T) {
+	watcher := staticWatcher{err: errors.New("poll failed")}
+
+	limiter := NewAdaptiveLimiter(Config{
+		Calculator: Calculator{Min: 1, Max: 3},
+		Interval:   10 * time.Millisecond,
+	}, watcher)
+	defer limiter.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	if limiter.Capacity() != 3 {
+		t.Errorf("capacity is expected to stay at Max when the watcher errors, %d was obtained", limiter.Capacity())
+	}
+}
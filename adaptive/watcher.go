@@ -0,0 +1,64 @@
+package adaptive
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var errLoadAvgFormat = errors.New("adaptive: unexpected /proc/loadavg format")
+
+// LoadWatcher reports backoff when the 1-minute system load average per CPU
+// core exceeds Threshold. It reads /proc/loadavg and is only meaningful on
+// platforms that expose it (Linux); on other platforms Poll always returns
+// an error and callers should omit this watcher.
+type LoadWatcher struct {
+	// Threshold is the load average per core above which Poll reports
+	// backoff, e.g. 1.0. Zero or negative defaults to 1.0.
+	Threshold float64
+}
+
+// Poll implements Watcher.
+func (w LoadWatcher) Poll(ctx context.Context) (backoff bool, err error) {
+	load, err := readLoadAvg()
+	if err != nil {
+		return false, err
+	}
+
+	threshold := w.Threshold
+	if threshold <= 0 {
+		threshold = 1.0
+	}
+	return load/float64(runtime.NumCPU()) > threshold, nil
+}
+
+func readLoadAvg() (float64, error) {
+	data, err := ioutil.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, errLoadAvgFormat
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// MemoryWatcher reports backoff when the Go runtime's heap allocation
+// exceeds Threshold bytes.
+type MemoryWatcher struct {
+	// Threshold is the heap allocation, in bytes, above which Poll
+	// reports backoff.
+	Threshold uint64
+}
+
+// Poll implements Watcher.
+func (w MemoryWatcher) Poll(ctx context.Context) (backoff bool, err error) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc > w.Threshold, nil
+}
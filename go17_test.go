@@ -12,15 +12,15 @@ import (
 	"time"
 )
 
-func (sem semaphore) Flush() {
-	close(sem)
-	for range sem {
-	}
+func (sem *semaphore) Flush() {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	sem.cur = 0
 }
 
 func TestSemaphore_Acquire_InvalidTimeout(t *testing.T) {
 	sem := New(0)
-	defer sem.(semaphore).Flush()
+	defer sem.(*semaphore).Flush()
 
 	nothingToDo := func(context.CancelFunc) {}
 
@@ -48,7 +48,7 @@ func TestSemaphore_Capacity_Immutability(t *testing.T) {
 	capacity := 7
 
 	sem := New(capacity)
-	defer sem.(semaphore).Flush()
+	defer sem.(*semaphore).Flush()
 
 	if sem.Capacity() != capacity {
 		t.Errorf("capacity equals to %d is expected, %d was obtained", capacity, sem.Capacity())
@@ -66,7 +66,7 @@ func TestSemaphore_Capacity_Immutability(t *testing.T) {
 
 func TestSemaphore_Occupied_Linearity(t *testing.T) {
 	sem := New(7)
-	defer sem.(semaphore).Flush()
+	defer sem.(*semaphore).Flush()
 
 	ctx := context.Background()
 	for i := 0; i < sem.Capacity(); i++ {
@@ -91,7 +91,7 @@ func TestSemaphore_Release_TryToGetDeadLock(t *testing.T) {
 
 func TestSemaphore_Concurrently(t *testing.T) {
 	sem := New(int(math.Max(2.0, float64(runtime.GOMAXPROCS(0)))))
-	defer sem.(semaphore).Flush()
+	defer sem.(*semaphore).Flush()
 
 	var counter int32
 
@@ -124,7 +124,7 @@ func TestSemaphore_Concurrently(t *testing.T) {
 
 func BenchmarkSemaphore_Acquire(b *testing.B) {
 	ctx, sem := context.Background(), New(b.N)
-	defer sem.(semaphore).Flush()
+	defer sem.(*semaphore).Flush()
 
 	for i := 0; i < b.N; i++ {
 		_, _ = sem.Acquire(ctx)
@@ -137,7 +137,7 @@ func BenchmarkSemaphore_Acquire(b *testing.B) {
 
 func BenchmarkSemaphore_Acquire_Release(b *testing.B) {
 	ctx, sem := context.Background(), New(b.N)
-	defer sem.(semaphore).Flush()
+	defer sem.(*semaphore).Flush()
 
 	for i := 0; i < b.N; i++ {
 		_, _ = sem.Acquire(ctx)
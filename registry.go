@@ -0,0 +1,130 @@
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+var errMaxCapacityExceeded = errors.New("semaphore: requested capacity exceeds registry's max capacity")
+
+// Stats describes the capacity and current occupancy of a named semaphore,
+// e.g. for exposing on a health endpoint.
+type Stats struct {
+	Cap, Occupied int
+}
+
+// Registry owns a set of named semaphores keyed by operation class
+// (e.g. "clone", "index", "push"), each with an independent capacity.
+// It is meant for a single process that needs several bounded pools
+// protecting different expensive operations.
+type Registry struct {
+	// Default is the capacity assigned to a name that is acquired before
+	// it has been configured via NewRegistry or Configure.
+	Default int
+	// MaxCapacity, if positive, is the largest capacity that Configure
+	// and lazy creation accept; a larger one makes them return an error.
+	// Semaphores created by NewRegistry are not checked against it.
+	MaxCapacity int
+
+	mu   sync.Mutex
+	sems map[string]Semaphore
+}
+
+// NewRegistry constructs a Registry with a named semaphore for every entry
+// in defaults, each with the given capacity.
+func NewRegistry(defaults map[string]int) *Registry {
+	reg := &Registry{sems: make(map[string]Semaphore, len(defaults))}
+	for name, capacity := range defaults {
+		reg.sems[name] = New(capacity)
+	}
+	return reg
+}
+
+// Configure sets or replaces the capacity of the named semaphore.
+// Existing holders of the previous semaphore are unaffected.
+func (reg *Registry) Configure(name string, capacity int) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.MaxCapacity > 0 && capacity > reg.MaxCapacity {
+		return errMaxCapacityExceeded
+	}
+	if reg.sems == nil {
+		reg.sems = make(map[string]Semaphore)
+	}
+	reg.sems[name] = New(capacity)
+	return nil
+}
+
+// Acquire acquires a slot from the named semaphore, lazily creating it
+// with the registry's Default capacity if it doesn't exist yet.
+func (reg *Registry) Acquire(ctx context.Context, name string) (ReleaseFunc, error) {
+	sem, err := reg.semaphore(name)
+	if err != nil {
+		return nothing, err
+	}
+	return sem.Acquire(ctx)
+}
+
+// AcquireMulti acquires a slot from each of the named semaphores, always in
+// a stable lock order (sorted by name) to avoid deadlocking with another
+// caller acquiring an overlapping set of names. If any acquisition fails,
+// every slot already acquired is released before the error is returned.
+func (reg *Registry) AcquireMulti(ctx context.Context, names ...string) (ReleaseFunc, error) {
+	ordered := make([]string, len(names))
+	copy(ordered, names)
+	sort.Strings(ordered)
+
+	releases := make([]ReleaseFunc, 0, len(ordered))
+	for _, name := range ordered {
+		release, err := reg.Acquire(ctx, name)
+		if err != nil {
+			for _, release := range releases {
+				release()
+			}
+			return nothing, err
+		}
+		releases = append(releases, release)
+	}
+
+	return func() {
+		for _, release := range releases {
+			release()
+		}
+	}, nil
+}
+
+// Stats returns the capacity and occupancy of every named semaphore known
+// to the registry.
+func (reg *Registry) Stats() map[string]Stats {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	stats := make(map[string]Stats, len(reg.sems))
+	for name, sem := range reg.sems {
+		stats[name] = Stats{Cap: sem.Capacity(), Occupied: sem.Occupied()}
+	}
+	return stats
+}
+
+func (reg *Registry) semaphore(name string) (Semaphore, error) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.sems == nil {
+		reg.sems = make(map[string]Semaphore)
+	}
+	if sem, found := reg.sems[name]; found {
+		return sem, nil
+	}
+
+	capacity := reg.Default
+	if reg.MaxCapacity > 0 && capacity > reg.MaxCapacity {
+		return nil, errMaxCapacityExceeded
+	}
+	sem := New(capacity)
+	reg.sems[name] = sem
+	return sem, nil
+}
@@ -0,0 +1,88 @@
+package semaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Acquire_Configured(t *testing.T) {
+	reg := NewRegistry(map[string]int{"clone": 2})
+	ctx := context.Background()
+
+	release, err := reg.Acquire(ctx, "clone")
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	defer release()
+
+	stats := reg.Stats()
+	if stats["clone"].Cap != 2 || stats["clone"].Occupied != 1 {
+		t.Errorf("Cap: 2, Occupied: 1 is expected, %+v was obtained", stats["clone"])
+	}
+}
+
+func TestRegistry_Acquire_LazyDefault(t *testing.T) {
+	reg := NewRegistry(nil)
+	reg.Default = 3
+	ctx := context.Background()
+
+	release, err := reg.Acquire(ctx, "index")
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	defer release()
+
+	stats := reg.Stats()
+	if stats["index"].Cap != 3 {
+		t.Errorf("Cap: 3 is expected, %+v was obtained", stats["index"])
+	}
+}
+
+func TestRegistry_Acquire_MaxCapacityGuard(t *testing.T) {
+	reg := NewRegistry(nil)
+	reg.Default = 10
+	reg.MaxCapacity = 5
+
+	if _, err := reg.Acquire(context.Background(), "push"); err != errMaxCapacityExceeded {
+		t.Errorf("%q error is expected, %q was obtained", errMaxCapacityExceeded, err)
+	}
+}
+
+func TestRegistry_AcquireMulti_StableOrderAndRollback(t *testing.T) {
+	reg := NewRegistry(map[string]int{"clone": 1, "push": 0})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	release, err := reg.AcquireMulti(ctx, "push", "clone")
+	if err == nil {
+		release()
+		t.Fatal("an error is expected because push has no capacity")
+	}
+
+	stats := reg.Stats()
+	if stats["clone"].Occupied != 0 {
+		t.Errorf("clone is expected to be rolled back, %+v was obtained", stats["clone"])
+	}
+}
+
+func TestRegistry_AcquireMulti_Success(t *testing.T) {
+	reg := NewRegistry(map[string]int{"clone": 1, "push": 1})
+	ctx := context.Background()
+
+	release, err := reg.AcquireMulti(ctx, "push", "clone")
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	stats := reg.Stats()
+	if stats["clone"].Occupied != 1 || stats["push"].Occupied != 1 {
+		t.Errorf("both semaphores are expected to be occupied, %+v", stats)
+	}
+
+	release()
+	stats = reg.Stats()
+	if stats["clone"].Occupied != 0 || stats["push"].Occupied != 0 {
+		t.Errorf("both semaphores are expected to be released, %+v", stats)
+	}
+}
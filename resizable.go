@@ -0,0 +1,135 @@
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// Resizable provides the functionality of a semaphore whose capacity
+// can be grown or shrunk at runtime without dropping in-flight holders.
+type Resizable interface {
+	HealthChecker
+	Releaser
+
+	// Acquire tries to reduce the number of available slots for 1.
+	// The operation can be canceled using context. In this case
+	// an appropriate error will be returned.
+	// It must be safe to call Acquire concurrently on a single semaphore.
+	Acquire(ctx context.Context) (ReleaseFunc, error)
+	// Resize changes the capacity of the semaphore to newCapacity.
+	// Growing wakes as many waiters as the additional capacity allows.
+	// Shrinking below the number of currently occupied slots is allowed;
+	// the excess holders drain naturally as they call Release.
+	// It must be safe to call Resize concurrently on a single semaphore.
+	Resize(newCapacity int) error
+	// IsFull reports whether the semaphore has no available slots at the moment.
+	IsFull() bool
+}
+
+// NewResizable constructs a new thread-safe Resizable semaphore with the given capacity.
+func NewResizable(capacity int) Resizable {
+	r := &resizable{size: capacity}
+	r.waiters.Init()
+	return r
+}
+
+var errNegativeCapacity = errors.New("capacity must not be negative")
+
+type resizable struct {
+	mu      sync.Mutex
+	size    int
+	cur     int
+	waiters list.List
+}
+
+func (r *resizable) Acquire(ctx context.Context) (ReleaseFunc, error) {
+	r.mu.Lock()
+	if r.cur < r.size && r.waiters.Len() == 0 {
+		r.cur++
+		r.mu.Unlock()
+		return releaser(r), nil
+	}
+
+	ready := make(chan struct{})
+	elem := r.waiters.PushBack(ready)
+	r.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		r.mu.Lock()
+		select {
+		case <-ready:
+			// already granted, hand the slot to the next waiter in line
+			r.mu.Unlock()
+			_ = r.Release()
+		default:
+			r.waiters.Remove(elem)
+			r.mu.Unlock()
+		}
+		return nothing, errTimeout
+	case <-ready:
+		return releaser(r), nil
+	}
+}
+
+func (r *resizable) Capacity() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size
+}
+
+func (r *resizable) Occupied() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cur
+}
+
+func (r *resizable) IsFull() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cur >= r.size
+}
+
+func (r *resizable) Release() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cur == 0 {
+		return errEmpty
+	}
+	r.cur--
+
+	for r.cur < r.size {
+		elem := r.waiters.Front()
+		if elem == nil {
+			break
+		}
+		r.waiters.Remove(elem)
+		r.cur++
+		close(elem.Value.(chan struct{}))
+	}
+	return nil
+}
+
+func (r *resizable) Resize(newCapacity int) error {
+	if newCapacity < 0 {
+		return errNegativeCapacity
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.size = newCapacity
+	for r.cur < r.size {
+		elem := r.waiters.Front()
+		if elem == nil {
+			break
+		}
+		r.waiters.Remove(elem)
+		r.cur++
+		close(elem.Value.(chan struct{}))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package semaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResizable_Acquire_Release(t *testing.T) {
+	sem := NewResizable(2)
+	ctx := context.Background()
+
+	releaseA, err := sem.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	releaseB, err := sem.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if !sem.IsFull() {
+		t.Error("expected the semaphore to be full")
+	}
+
+	releaseA()
+	if sem.Occupied() != 1 {
+		t.Errorf("1 occupied place is expected, %d was obtained", sem.Occupied())
+	}
+	releaseB()
+}
+
+func TestResizable_Resize_Grow(t *testing.T) {
+	sem := NewResizable(1)
+	ctx := context.Background()
+
+	_, err := sem.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sem.Acquire(ctx)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := sem.Resize(2); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the blocked waiter to be granted after growing the capacity")
+	}
+}
+
+func TestResizable_Resize_ShrinkDrainsNaturally(t *testing.T) {
+	sem := NewResizable(2)
+	ctx := context.Background()
+
+	releaseA, err := sem.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	releaseB, err := sem.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	if err := sem.Resize(1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if sem.Capacity() != 1 {
+		t.Errorf("capacity equals to 1 is expected, %d was obtained", sem.Capacity())
+	}
+	if sem.Occupied() != 2 {
+		t.Errorf("2 occupied places are expected, %d were obtained", sem.Occupied())
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sem.Acquire(ctx)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	select {
+	case <-done:
+		t.Fatal("the new waiter must not be granted while the semaphore is over capacity")
+	default:
+	}
+
+	releaseA()
+
+	select {
+	case <-done:
+		t.Fatal("the new waiter must not be granted while the semaphore is still over capacity")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releaseB()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiter to be granted once all excess holders drained")
+	}
+}
+
+func TestResizable_Resize_Negative(t *testing.T) {
+	sem := NewResizable(1)
+	if err := sem.Resize(-1); err != errNegativeCapacity {
+		t.Errorf("%q error is expected, %q was obtained", errNegativeCapacity, err)
+	}
+}
@@ -1,8 +1,10 @@
 package semaphore // import "github.com/kamilsk/semaphore"
 
 import (
+	"container/list"
 	"context"
 	"errors"
+	"sync"
 )
 
 // HealthChecker defines helpful methods related with semaphore status.
@@ -37,11 +39,21 @@ type Semaphore interface {
 	// an appropriate error will be returned.
 	// It must be safe to call Acquire concurrently on a single semaphore.
 	Acquire(ctx context.Context) (ReleaseFunc, error)
+	// AcquireN tries to reduce the number of available slots for n at once
+	// and returns a single ReleaseFunc releasing all of them. A caller is
+	// woken only once all n slots are simultaneously available, so a large
+	// request cannot be indefinitely overtaken by a stream of smaller ones.
+	// The operation can be canceled using context. In this case
+	// an appropriate error will be returned and no slots will be held.
+	// It must be safe to call AcquireN concurrently on a single semaphore.
+	AcquireN(ctx context.Context, n int) (ReleaseFunc, error)
 }
 
 // New constructs a new thread-safe Semaphore with the given capacity.
 func New(capacity int) Semaphore {
-	return make(semaphore, capacity)
+	sem := &semaphore{size: capacity}
+	sem.waiters.Init()
+	return sem
 }
 
 var (
@@ -51,32 +63,110 @@ var (
 	errTimeout = errors.New("operation timeout")
 )
 
-type semaphore chan struct{}
+type waiter struct {
+	n     int
+	ready chan struct{}
+}
+
+type semaphore struct {
+	mu      sync.Mutex
+	size    int
+	cur     int
+	waiters list.List
+}
+
+func (sem *semaphore) Acquire(ctx context.Context) (ReleaseFunc, error) {
+	return sem.AcquireN(ctx, 1)
+}
+
+func (sem *semaphore) AcquireN(ctx context.Context, n int) (ReleaseFunc, error) {
+	sem.mu.Lock()
+	if sem.cur+n <= sem.size && sem.waiters.Len() == 0 {
+		sem.cur += n
+		sem.mu.Unlock()
+		return sem.releaseFunc(n), nil
+	}
+
+	if n > sem.size {
+		// Don't make other waiters block on a request that's doomed to fail.
+		sem.mu.Unlock()
+		<-ctx.Done()
+		return nothing, errTimeout
+	}
+
+	w := &waiter{n: n, ready: make(chan struct{})}
+	elem := sem.waiters.PushBack(w)
+	sem.mu.Unlock()
 
-func (sem semaphore) Acquire(ctx context.Context) (ReleaseFunc, error) {
 	select {
-	case sem <- struct{}{}:
-		return releaser(sem), nil
 	case <-ctx.Done():
+		sem.mu.Lock()
+		select {
+		case <-w.ready:
+			// already granted, hand the slots to the next waiter in line
+			sem.mu.Unlock()
+			sem.release(n)
+		default:
+			sem.waiters.Remove(elem)
+			sem.mu.Unlock()
+		}
 		return nothing, errTimeout
+	case <-w.ready:
+		return sem.releaseFunc(n), nil
 	}
 }
 
-func (sem semaphore) Capacity() int {
-	return cap(sem)
+func (sem *semaphore) Capacity() int {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	return sem.size
 }
 
-func (sem semaphore) Occupied() int {
-	return len(sem)
+func (sem *semaphore) Occupied() int {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	return sem.cur
 }
 
-func (sem semaphore) Release() error {
-	select {
-	case <-sem:
-		return nil
-	default:
+func (sem *semaphore) Release() error {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+
+	if sem.cur < 1 {
 		return errEmpty
 	}
+	sem.releaseLocked(1)
+	return nil
+}
+
+// release decrements cur by n and wakes waiters at the front of the queue
+// whose request now fits into the remaining capacity.
+func (sem *semaphore) release(n int) {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	sem.releaseLocked(n)
+}
+
+// releaseLocked assumes sem.mu is held. It stops at the first waiter that
+// doesn't fit, rather than skipping ahead to smaller ones behind it, so a
+// large request can't be starved by a steady stream of smaller ones.
+func (sem *semaphore) releaseLocked(n int) {
+	sem.cur -= n
+	for elem := sem.waiters.Front(); elem != nil; {
+		w := elem.Value.(*waiter)
+		if sem.cur+w.n > sem.size {
+			break
+		}
+		next := elem.Next()
+		sem.cur += w.n
+		sem.waiters.Remove(elem)
+		close(w.ready)
+		elem = next
+	}
+}
+
+func (sem *semaphore) releaseFunc(n int) ReleaseFunc {
+	return func() { sem.release(n) }
 }
 
 func releaser(releaser Releaser) ReleaseFunc {
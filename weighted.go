@@ -0,0 +1,127 @@
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Weighted provides the functionality of a semaphore that can be acquired
+// and released in arbitrary-sized chunks, e.g. a large job occupies
+// several units while a small one occupies just one.
+type Weighted interface {
+	// Acquire reduces the number of available units by n.
+	// The operation can be canceled using context. In this case
+	// an appropriate error will be returned and no units will be held.
+	// It must be safe to call Acquire concurrently on a single Weighted.
+	Acquire(ctx context.Context, n int64) error
+	// TryAcquire tries to reduce the number of available units by n
+	// without blocking. On success returns true, otherwise false and
+	// the Weighted is left untouched.
+	TryAcquire(n int64) bool
+	// Release releases n previously acquired units.
+	// It must be safe to call Release concurrently on a single Weighted.
+	Release(n int64)
+	// Capacity returns the total number of units of the Weighted.
+	// It must be safe to call Capacity concurrently on a single Weighted.
+	Capacity() int64
+	// Occupied returns the current number of occupied units.
+	// It must be safe to call Occupied concurrently on a single Weighted.
+	Occupied() int64
+}
+
+// NewWeighted constructs a new thread-safe Weighted semaphore with the given capacity.
+func NewWeighted(capacity int64) Weighted {
+	w := &weighted{size: capacity}
+	w.waiters.Init()
+	return w
+}
+
+type weightedWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+type weighted struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+func (w *weighted) Acquire(ctx context.Context, n int64) error {
+	w.mu.Lock()
+	if w.cur+n <= w.size && w.waiters.Len() == 0 {
+		w.cur += n
+		w.mu.Unlock()
+		return nil
+	}
+
+	if n > w.size {
+		w.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	waiter := weightedWaiter{n: n, ready: make(chan struct{})}
+	elem := w.waiters.PushBack(&waiter)
+	w.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		w.mu.Lock()
+		select {
+		case <-waiter.ready:
+			// already granted, hand the units to the next waiter in line
+			w.mu.Unlock()
+			w.Release(n)
+		default:
+			w.waiters.Remove(elem)
+			w.mu.Unlock()
+		}
+		return ctx.Err()
+	case <-waiter.ready:
+		return nil
+	}
+}
+
+func (w *weighted) TryAcquire(n int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cur+n <= w.size && w.waiters.Len() == 0 {
+		w.cur += n
+		return true
+	}
+	return false
+}
+
+func (w *weighted) Capacity() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+func (w *weighted) Occupied() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cur
+}
+
+func (w *weighted) Release(n int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cur -= n
+	for elem := w.waiters.Front(); elem != nil; {
+		waiter := elem.Value.(*weightedWaiter)
+		if w.cur+waiter.n > w.size {
+			break
+		}
+		next := elem.Next()
+		w.cur += waiter.n
+		w.waiters.Remove(elem)
+		close(waiter.ready)
+		elem = next
+	}
+}
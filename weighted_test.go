@@ -0,0 +1,205 @@
+package semaphore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWeighted_Acquire_Release(t *testing.T) {
+	w := NewWeighted(10)
+	ctx := context.Background()
+
+	if err := w.Acquire(ctx, 4); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if err := w.Acquire(ctx, 6); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if w.TryAcquire(1) {
+		t.Error("expected the semaphore to be fully occupied")
+	}
+
+	w.Release(4)
+	if !w.TryAcquire(4) {
+		t.Error("expected to acquire the just released units")
+	}
+
+	w.Release(10)
+}
+
+func TestWeighted_Capacity_Occupied(t *testing.T) {
+	w := NewWeighted(10)
+	ctx := context.Background()
+
+	if w.Capacity() != 10 {
+		t.Errorf("capacity equals to 10 is expected, %d was obtained", w.Capacity())
+	}
+	if w.Occupied() != 0 {
+		t.Errorf("0 occupied units are expected, %d were obtained", w.Occupied())
+	}
+
+	if err := w.Acquire(ctx, 4); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if w.Occupied() != 4 {
+		t.Errorf("4 occupied units are expected, %d were obtained", w.Occupied())
+	}
+	if w.Capacity() != 10 {
+		t.Errorf("capacity equals to 10 is expected, %d was obtained", w.Capacity())
+	}
+
+	w.Release(4)
+	if w.Occupied() != 0 {
+		t.Errorf("0 occupied units are expected, %d were obtained", w.Occupied())
+	}
+}
+
+func TestWeighted_Acquire_Timeout(t *testing.T) {
+	w := NewWeighted(1)
+	ctx := context.Background()
+
+	if err := w.Acquire(ctx, 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if err := w.Acquire(timeout, 1); err != timeout.Err() {
+		t.Errorf("a timeout error is expected, %q was obtained", err)
+	}
+}
+
+func TestWeighted_Acquire_FairQueueing(t *testing.T) {
+	w := NewWeighted(2)
+	ctx := context.Background()
+
+	if err := w.Acquire(ctx, 2); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	order := make(chan int, 2)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := w.Acquire(ctx, 2); err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+			return
+		}
+		order <- 2
+		w.Release(2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := w.Acquire(ctx, 1); err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+			return
+		}
+		order <- 1
+		w.Release(1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	w.Release(2)
+	wg.Wait()
+	close(order)
+
+	first := <-order
+	if first != 2 {
+		t.Errorf("the head-of-line waiter requesting 2 units is expected to run first, %d ran first", first)
+	}
+}
+
+func TestWeighted_Acquire_StrictFIFO_NoSkipAhead(t *testing.T) {
+	w := NewWeighted(2)
+	ctx := context.Background()
+
+	if err := w.Acquire(ctx, 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+	if err := w.Acquire(ctx, 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	bigDone := make(chan error, 1)
+	go func() {
+		err := w.Acquire(ctx, 2)
+		if err == nil {
+			w.Release(2)
+		}
+		bigDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	smallDone := make(chan error, 1)
+	go func() {
+		err := w.Acquire(ctx, 1)
+		if err == nil {
+			w.Release(1)
+		}
+		smallDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	w.Release(1)
+
+	select {
+	case <-bigDone:
+		t.Fatal("the big waiter must still be blocked, only 1 of the 2 needed units was freed")
+	case <-smallDone:
+		t.Fatal("the small waiter must not skip ahead of the earlier, still-unsatisfied big waiter")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	w.Release(1)
+
+	select {
+	case err := <-bigDone:
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the big waiter to be granted once both units were free")
+	}
+	select {
+	case err := <-smallDone:
+		if err != nil {
+			t.Errorf("error is not expected, %q was obtained", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the small waiter to be granted after the big waiter released")
+	}
+}
+
+func TestWeighted_Acquire_CanceledWaiterIsDequeued(t *testing.T) {
+	w := NewWeighted(1)
+	ctx := context.Background()
+
+	if err := w.Acquire(ctx, 1); err != nil {
+		t.Fatalf("error is not expected, %q was obtained", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Acquire(canceled, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err == nil {
+		t.Error("an error is expected for the canceled waiter")
+	}
+
+	w.Release(1)
+	if !w.TryAcquire(1) {
+		t.Error("expected the released unit to be available once more")
+	}
+}